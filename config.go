@@ -0,0 +1,103 @@
+package gwargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+/*
+resolveField determines the value(s) for a single field, applying
+gwargs' fallback order: the CLI arguments in args win first, then the
+field's `env` variable if set, then configValues (loaded from a
+`--config` file, if any), then the tag's `default`, and finally
+nothing at all (the Go zero value). ok reports whether any source had
+a value.
+*/
+func resolveField(args map[string][]string, configValues map[string]string, name string, tag tagInfo) (values []string, ok bool) {
+	if values, ok = lookupArgAll(args, name, tag.short); ok {
+		return values, true
+	}
+
+	if tag.env != "" {
+		if value, found := os.LookupEnv(tag.env); found {
+			return []string{value}, true
+		}
+	}
+
+	if value, found := configValues[name]; found {
+		return []string{value}, true
+	}
+
+	if tag.def != "" {
+		return []string{tag.def}, true
+	}
+
+	return nil, false
+}
+
+// loadConfigArg reads the path given via a top-level `--config` flag,
+// if one was passed, and flattens it into a map of string values. It
+// returns a nil map if no `--config` flag was given.
+func loadConfigArg(args map[string][]string) (map[string]string, error) {
+	values, ok := lookupArgAll(args, "config")
+	if !ok || len(values) == 0 {
+		return nil, nil
+	}
+
+	return loadConfigFile(values[len(values)-1])
+}
+
+/*
+loadConfigFile reads the file at path and flattens its top-level
+values into strings, so a config file feeds the same per-kind parsing
+Parse already uses for CLI arguments and environment variables.
+
+Only JSON is supported for now; TOML support would pull in a
+dependency this module doesn't otherwise carry.
+*/
+func loadConfigFile(path string) (map[string]string, error) {
+	switch ext := filepath.Ext(path); ext {
+	case ".json", "":
+		return loadJSONConfig(path)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension '%s'", ext)
+	}
+}
+
+func loadJSONConfig(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, leaf := range raw {
+		values[key] = stringifyConfigValue(leaf)
+	}
+
+	return values, nil
+}
+
+// stringifyConfigValue renders a decoded JSON leaf as a string so it
+// can flow through the same per-kind parsing as any other argument
+// source.
+func stringifyConfigValue(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
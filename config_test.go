@@ -0,0 +1,109 @@
+package gwargs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveFieldPrecedence(t *testing.T) {
+	t.Setenv("GWARGS_TEST_PORT", "9000")
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	writeFile(t, configPath, `{"port": 7000, "host": "config-host"}`)
+
+	tag := tagInfo{name: "port", env: "GWARGS_TEST_PORT", def: "1000"}
+	configValues, err := loadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+
+	// CLI arg wins over everything else.
+	args := map[string][]string{"port": {"8080"}}
+	values, ok := resolveField(args, configValues, "port", tag)
+	if !ok || values[len(values)-1] != "8080" {
+		t.Fatalf("resolveField with CLI arg = %v, %v, want [8080], true", values, ok)
+	}
+
+	// No CLI arg: falls back to the env var.
+	values, ok = resolveField(nil, configValues, "port", tag)
+	if !ok || values[len(values)-1] != "9000" {
+		t.Fatalf("resolveField with env fallback = %v, %v, want [9000], true", values, ok)
+	}
+
+	// No CLI arg or env var: falls back to the config file.
+	noEnvTag := tagInfo{name: "port", def: "1000"}
+	values, ok = resolveField(nil, configValues, "port", noEnvTag)
+	if !ok || values[len(values)-1] != "7000" {
+		t.Fatalf("resolveField with config fallback = %v, %v, want [7000], true", values, ok)
+	}
+
+	// No CLI arg, env var, or config value: falls back to the tag default.
+	noneTag := tagInfo{name: "missing", def: "1000"}
+	values, ok = resolveField(nil, configValues, "missing", noneTag)
+	if !ok || values[len(values)-1] != "1000" {
+		t.Fatalf("resolveField with default fallback = %v, %v, want [1000], true", values, ok)
+	}
+
+	// Nothing at all: reports absent.
+	values, ok = resolveField(nil, nil, "missing", tagInfo{name: "missing"})
+	if ok || values != nil {
+		t.Fatalf("resolveField with nothing set = %v, %v, want nil, false", values, ok)
+	}
+}
+
+func TestLoadConfigFileJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	writeFile(t, path, `{"name": "svc", "count": 3, "enabled": true}`)
+
+	values, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+
+	if values["name"] != "svc" {
+		t.Errorf("name = %q, want %q", values["name"], "svc")
+	}
+	if values["count"] != "3" {
+		t.Errorf("count = %q, want %q", values["count"], "3")
+	}
+	if values["enabled"] != "true" {
+		t.Errorf("enabled = %q, want %q", values["enabled"], "true")
+	}
+}
+
+func TestLoadConfigFileUnsupportedExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	writeFile(t, path, `name = "svc"`)
+
+	if _, err := loadConfigFile(path); err == nil {
+		t.Fatal("expected an error for an unsupported config file extension")
+	}
+}
+
+func TestParseUsesConfigFileFallback(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	writeFile(t, path, `{"port": 9090}`)
+
+	var s struct {
+		Port int `gwargs:"name=port"`
+	}
+
+	if err := parse(&s, []string{"--config", path}, &Config{}); err != nil {
+		t.Fatalf("parse returned error: %v", err)
+	}
+	if s.Port != 9090 {
+		t.Errorf("Port = %d, want 9090", s.Port)
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
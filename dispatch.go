@@ -0,0 +1,129 @@
+package gwargs
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+/*
+Command pairs a subcommand's name with the struct Dispatch should
+populate from the remaining arguments when that subcommand is
+selected.
+*/
+type Command struct {
+	Name string
+	Args any
+}
+
+/*
+Dispatch implements git-style subcommand CLIs, e.g. `app serve --port
+8080` or `app migrate up`. root is parsed first and holds any flags
+shared across every subcommand; subs maps each subcommand name to the
+struct that holds its own flags. config is forwarded to Parse
+unchanged.
+
+The first positional argument is treated as the subcommand name; the
+rest of os.Args is parsed into the matching struct from subs. An
+unknown or missing subcommand name returns an error listing every
+registered subcommand alongside its struct's tag-defined help text.
+*/
+func Dispatch(root any, subs map[string]any, config *Config) error {
+	if err := Parse(root, config); err != nil {
+		return err
+	}
+
+	name, rest := splitSubcommand(os.Args[1:])
+	if name == "" {
+		return fmt.Errorf("missing subcommand\n\n%s", listCommands(subs))
+	}
+
+	selected, ok := subs[name]
+	if !ok {
+		return fmt.Errorf("unknown subcommand '%s'\n\n%s", name, listCommands(subs))
+	}
+
+	return parse(selected, rest, config)
+}
+
+/*
+Positionals returns the bare, non-flag arguments in args, in the
+order they appear. It applies the same rules mapArgSlice uses to tell
+a flag's value from a standalone argument, so `--tag value extra`
+reports only `extra` as positional. Dispatch does not call it (it
+finds the subcommand name with splitSubcommand, which applies the
+same rules); callers with their own positional arguments (e.g.
+`app copy src dst`) can call it directly on os.Args[1:].
+*/
+func Positionals(args []string) []string {
+	var positionals []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if strings.HasPrefix(arg, "--") {
+			if !strings.Contains(arg, "=") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+			}
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		positionals = append(positionals, arg)
+	}
+
+	return positionals
+}
+
+// splitSubcommand finds the first bare, non-flag argument in args and
+// returns it along with args minus that one element, preserving the
+// order of everything else. It returns an empty name if args has no
+// positional argument at all.
+func splitSubcommand(args []string) (name string, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if strings.HasPrefix(arg, "--") {
+			if !strings.Contains(arg, "=") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+			}
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		rest = make([]string, 0, len(args)-1)
+		rest = append(rest, args[:i]...)
+		rest = append(rest, args[i+1:]...)
+		return arg, rest
+	}
+
+	return "", args
+}
+
+// listCommands renders every registered subcommand, sorted by name,
+// each followed by its struct's tag-defined help text.
+func listCommands(subs map[string]any) string {
+	commands := make([]Command, 0, len(subs))
+	for name, args := range subs {
+		commands = append(commands, Command{Name: name, Args: args})
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+
+	var b strings.Builder
+	b.WriteString("Available commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "  %s\n", cmd.Name)
+		for _, line := range usageLines(cmd.Args) {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+
+	return b.String()
+}
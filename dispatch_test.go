@@ -0,0 +1,107 @@
+package gwargs
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func withArgs(t *testing.T, args []string, fn func()) {
+	t.Helper()
+	old := os.Args
+	os.Args = append([]string{"app"}, args...)
+	defer func() { os.Args = old }()
+	fn()
+}
+
+func TestDispatchRoutesToSelectedSubcommand(t *testing.T) {
+	var root struct {
+		Verbose bool `gwargs:"name=verbose,short=v"`
+	}
+	var serve struct {
+		Port int `gwargs:"name=port"`
+	}
+	var migrate struct {
+		Direction string `gwargs:"name=direction"`
+	}
+
+	withArgs(t, []string{"-v", "serve", "--port", "8080"}, func() {
+		err := Dispatch(&root, map[string]any{
+			"serve":   &serve,
+			"migrate": &migrate,
+		}, &Config{})
+		if err != nil {
+			t.Fatalf("Dispatch returned error: %v", err)
+		}
+	})
+
+	if !root.Verbose {
+		t.Error("root.Verbose should be true")
+	}
+	if serve.Port != 8080 {
+		t.Errorf("serve.Port = %d, want 8080", serve.Port)
+	}
+}
+
+func TestDispatchUnknownSubcommandListsCommands(t *testing.T) {
+	var root struct{}
+	var serve struct {
+		Port int `gwargs:"name=port,help=port to listen on"`
+	}
+
+	var err error
+	withArgs(t, []string{"bogus"}, func() {
+		err = Dispatch(&root, map[string]any{"serve": &serve}, &Config{})
+	})
+
+	if err == nil {
+		t.Fatal("expected an error for an unknown subcommand")
+	}
+	if !strings.Contains(err.Error(), "unknown subcommand 'bogus'") {
+		t.Errorf("error %q should name the unknown subcommand", err.Error())
+	}
+	if !strings.Contains(err.Error(), "serve") || !strings.Contains(err.Error(), "port to listen on") {
+		t.Errorf("error %q should list registered subcommands and their help text", err.Error())
+	}
+}
+
+func TestDispatchMissingSubcommand(t *testing.T) {
+	var root struct{}
+	var serve struct{}
+
+	var err error
+	withArgs(t, nil, func() {
+		err = Dispatch(&root, map[string]any{"serve": &serve}, &Config{})
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "missing subcommand") {
+		t.Fatalf("expected a missing subcommand error, got: %v", err)
+	}
+}
+
+func TestSplitSubcommand(t *testing.T) {
+	name, rest := splitSubcommand([]string{"--config", "app.json", "serve", "--port", "8080"})
+	if name != "serve" {
+		t.Fatalf("name = %q, want %q", name, "serve")
+	}
+	if strings.Join(rest, " ") != "--config app.json --port 8080" {
+		t.Fatalf("rest = %v, want the subcommand token removed in place", rest)
+	}
+}
+
+func TestSplitSubcommandNoPositional(t *testing.T) {
+	name, rest := splitSubcommand([]string{"--config", "app.json"})
+	if name != "" {
+		t.Fatalf("name = %q, want empty", name)
+	}
+	if len(rest) != 2 {
+		t.Fatalf("rest = %v, want the original args back", rest)
+	}
+}
+
+func TestPositionals(t *testing.T) {
+	got := Positionals([]string{"--tag", "value", "extra", "-f"})
+	if len(got) != 1 || got[0] != "extra" {
+		t.Fatalf("Positionals = %v, want [extra]", got)
+	}
+}
@@ -3,10 +3,8 @@ package gwargs
 import (
 	"errors"
 	"fmt"
-	"math"
 	"os"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -24,10 +22,35 @@ from occurring and will result in an error.
 
 The config argument is reserved for future use.
 
-Currently, the only supported types are string, bool, and
-signed and unsigned ints and floats.
+Currently supported types are string, bool, signed and
+unsigned ints, floats, complex numbers, time.Duration, time.Time,
+slices and string-keyed maps of any of the above, and any type
+implementing Unmarshaler or encoding.TextUnmarshaler.
+
+Fields may carry a `gwargs:"..."` tag to control how they are
+populated; see parseTag for the supported keys. When a field
+has no tag, its Go field name is used as the long flag name.
+Fields tagged `required` that end up with no value from any
+source are collected and reported together in a single error.
+A `short` tag is only valid on a bool field, since single-dash
+arguments are parsed as combinable boolean flags (see
+mapArgSlice); tagging any other kind with `short` is an error.
+
+A field tagged `env=NAME` falls back to that environment
+variable when absent from the command line, and a `--config`
+flag pointing at a JSON file supplies a further fallback below
+that. Resolution order is: CLI flag, then env var, then config
+file value, then the tag's `default`, then the Go zero value.
 */
 func Parse(s any, config *Config) error {
+	return parse(s, os.Args[1:], config)
+}
+
+// parse is the shared implementation behind Parse and Dispatch: it
+// populates s from argv instead of always reading os.Args, so Dispatch
+// can hand a subcommand struct whatever arguments remain after the
+// subcommand name has been peeled off.
+func parse(s any, argv []string, config *Config) error {
 	t := reflect.TypeOf(s)
 	v := reflect.ValueOf(s)
 
@@ -42,97 +65,117 @@ func Parse(s any, config *Config) error {
 	}
 
 	deRef := v.Elem()
-	args := mapArgSlice(os.Args[1:])
+	args := mapArgSlice(argv)
+
+	configValues, err := loadConfigArg(args)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
 
 	for i := 0; i < deRef.NumField(); i++ {
 		field := t.Elem().Field(i)
 		val := deRef.Field(i)
+		tag := parseTag(field.Tag.Get("gwargs"))
 
-		switch val.Kind() {
+		name := tag.name
+		if name == "" {
+			name = field.Name
+		}
 
-		case reflect.String:
-			val.SetString(args[field.Name])
+		if tag.short != "" && val.Kind() != reflect.Bool {
+			return fmt.Errorf(
+				"field '%s' has a short flag but is not bool; short flags only support boolean fields",
+				name,
+			)
+		}
 
-		case reflect.Bool:
-			if value, ok := args[field.Name]; ok {
-				val.SetBool(strings.EqualFold(value, "true"))
-			}
+		values, present := resolveField(args, configValues, name, tag)
+		if !present && tag.required {
+			missing = append(missing, name)
+			continue
+		}
 
-		case
-			reflect.Int,
-			reflect.Int8,
-			reflect.Int16,
-			reflect.Int32,
-			reflect.Int64:
+		var value string
+		if len(values) > 0 {
+			value = values[len(values)-1]
+		}
 
-			n, err := strconv.ParseInt(args[field.Name], 10, 64)
+		if ok, err := assignSpecial(val, value, present); ok {
 			if err != nil {
 				return err
 			}
-			if ok := checkIntOverflow(n, val.Kind()); !ok {
-				return fmt.Errorf("overflow detected, cannot fit %v into %s", n, val.Kind())
-			}
-			val.SetInt(n)
-
-		case
-			reflect.Uint,
-			reflect.Uint8,
-			reflect.Uint16,
-			reflect.Uint32,
-			reflect.Uint64:
-
-			if strings.Contains(args[field.Name], "-") {
-				return fmt.Errorf(
-					"underflow detected, cannot fit '%v' into '%s'",
-					args[field.Name],
-					val.Kind(),
-				)
-			}
+			continue
+		}
 
-			n, err := strconv.ParseUint(args[field.Name], 10, 64)
-			if err != nil {
+		switch val.Kind() {
+
+		case reflect.Slice:
+			if !present {
+				continue
+			}
+			if err := assignSlice(val, values, field.Name); err != nil {
 				return err
 			}
 
-			if ok := checkUIntOverflow(n, val.Kind()); !ok {
-				return fmt.Errorf("overflow detected, cannot fit %v into %s", n, val.Kind())
+		case reflect.Map:
+			if !present {
+				continue
 			}
-			val.SetUint(n)
-
-		case
-			reflect.Float32,
-			reflect.Float64:
-
-			n, err := strconv.ParseFloat(args[field.Name], 64)
-			if err != nil {
+			if err := assignMap(val, values, field.Name); err != nil {
 				return err
 			}
-			if ok := checkFloatOverflow(n, val.Kind()); !ok {
-				return fmt.Errorf("overflow detected, cannot fit %v into %s", n, val.Kind())
-			}
-			val.SetFloat(n)
 
 		default:
-			return fmt.Errorf("unsupported type '%s' in field '%s'", val.Kind(), field.Name)
+			if !present {
+				continue
+			}
+			if err := assignScalar(val, value, field.Name); err != nil {
+				return err
+			}
 		}
 	}
 
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required field(s): %s", strings.Join(missing, ", "))
+	}
+
 	return nil
 }
 
+// lookupArgAll returns every occurrence recorded in args across the
+// given candidate keys, in the order they appeared on the command
+// line. ok reports whether any of the keys was present at all.
+func lookupArgAll(args map[string][]string, keys ...string) (values []string, ok bool) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if found, present := args[key]; present {
+			ok = true
+			values = append(values, found...)
+		}
+	}
+	return values, ok
+}
+
 /*
-Parses a unix-style slice of arguments into a map
+Parses a unix-style slice of arguments into a map of flag name to
+every value it was given.
 
 Arguments starting with two dashes '--' are treated as named
-arguments and will split on '=' if present OR take the next
-arg in the slice.
+arguments and will split on '=' if present OR take the next arg
+in the slice. A flag repeated across multiple occurrences, e.g.
+`--tag a --tag b`, accumulates every value in order rather than
+the last one winning.
 
 Arguments starting with a single dash '-' are treated as
 boolean flags and split on empty space, e.g. -lahR results
 in a map entry for l, a, h, and R.
 */
-func mapArgSlice(args []string) map[string]string {
-	res := map[string]string{}
+func mapArgSlice(args []string) map[string][]string {
+	res := map[string][]string{}
 
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
@@ -143,17 +186,16 @@ func mapArgSlice(args []string) map[string]string {
 
 			if strings.Contains(arg, "=") {
 				parts := strings.SplitN(arg, "=", 2)
-				res[parts[0]] = parts[1]
+				res[parts[0]] = append(res[parts[0]], parts[1])
 				continue
 			}
 
-			next := args[i+1]
-			if strings.HasPrefix(next, "-") {
-				res[arg] = ""
+			if i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+				res[arg] = append(res[arg], "")
 				continue
 			}
 
-			res[arg] = next
+			res[arg] = append(res[arg], args[i+1])
 
 			i++
 			continue
@@ -162,71 +204,34 @@ func mapArgSlice(args []string) map[string]string {
 		// Single character flags
 		if strings.HasPrefix(arg, "-") {
 			for _, flag := range strings.Split(arg[1:], "") {
-				res[flag] = ""
+				res[flag] = append(res[flag], "")
 			}
 			continue
 		}
 
 		// Junk
-		res[arg] = ""
+		res[arg] = append(res[arg], "")
 	}
 
 	return res
 }
 
-// Reports whether an unknown int will overflow
-func checkIntOverflow(n int64, t reflect.Kind) (ok bool) {
-	switch t {
-	case reflect.Int8:
-		return !(n > math.MaxInt8 || n < math.MinInt8)
-
-	case reflect.Int16:
-		return !(n > math.MaxInt16 || n < math.MinInt16)
-
-	case reflect.Int32:
-		return !(n > math.MaxInt32 || n < math.MinInt32)
-
-	case
-		reflect.Int,
-		reflect.Int64:
-
-		return !(n > math.MaxInt64 || n < math.MinInt64)
-
-	default:
-		return false
-	}
+// Reports whether n fits in val's underlying int type without overflowing.
+func checkIntOverflow(val reflect.Value, n int64) bool {
+	return !val.OverflowInt(n)
 }
 
-// Reports whether an unsigned integer will overflow
-func checkUIntOverflow(n uint64, k reflect.Kind) (ok bool) {
-	switch k {
-	case reflect.Uint8:
-		return !(n > math.MaxUint8 || n < 0)
-
-	case reflect.Uint16:
-		return !(n > math.MaxUint16 || n < 0)
-
-	case reflect.Uint32:
-		return !(n > math.MaxUint32 || n < 0)
-
-	case reflect.Uint, reflect.Uint64:
-		return !(n > math.MaxUint64 || n < 0)
-
-	default:
-		return false
-	}
+// Reports whether n fits in val's underlying uint type without overflowing.
+func checkUIntOverflow(val reflect.Value, n uint64) bool {
+	return !val.OverflowUint(n)
 }
 
-// Reports whether an unknown float will overflow
-func checkFloatOverflow(n float64, k reflect.Kind) (ok bool) {
-	switch k {
-	case reflect.Float32:
-		return !(n > math.MaxFloat32 || n < math.SmallestNonzeroFloat32)
-
-	case reflect.Float64:
-		return !(n > math.MaxFloat64 || n < math.SmallestNonzeroFloat64)
+// Reports whether n fits in val's underlying float type without overflowing.
+func checkFloatOverflow(val reflect.Value, n float64) bool {
+	return !val.OverflowFloat(n)
+}
 
-	default:
-		return false
-	}
+// Reports whether n fits in val's underlying complex type without overflowing.
+func checkComplexOverflow(val reflect.Value, n complex128) bool {
+	return !val.OverflowComplex(n)
 }
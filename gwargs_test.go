@@ -0,0 +1,39 @@
+package gwargs
+
+import "testing"
+
+func TestParseOptionalFieldsStayAtZeroValueWhenAbsent(t *testing.T) {
+	var s struct {
+		Verbose bool   `gwargs:"name=verbose"`
+		Count   int    `gwargs:"name=count"`
+		Name    string `gwargs:"name=name"`
+	}
+
+	if err := parse(&s, nil, &Config{}); err != nil {
+		t.Fatalf("parse with no args returned error: %v", err)
+	}
+
+	if s.Verbose {
+		t.Error("Verbose should stay false when --verbose is never passed")
+	}
+	if s.Count != 0 {
+		t.Errorf("Count should stay 0 when --count is never passed, got %d", s.Count)
+	}
+	if s.Name != "" {
+		t.Errorf("Name should stay empty when --name is never passed, got %q", s.Name)
+	}
+}
+
+func TestParseBoolFlagPresentWithoutValue(t *testing.T) {
+	var s struct {
+		Verbose bool `gwargs:"name=verbose,short=v"`
+	}
+
+	if err := parse(&s, []string{"-v"}, &Config{}); err != nil {
+		t.Fatalf("parse(-v) returned error: %v", err)
+	}
+
+	if !s.Verbose {
+		t.Error("Verbose should be true when -v is passed with no value")
+	}
+}
@@ -0,0 +1,112 @@
+package gwargs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+tagInfo holds the parsed contents of a single field's `gwargs`
+struct tag.
+*/
+type tagInfo struct {
+	name     string
+	short    string
+	def      string
+	required bool
+	help     string
+	env      string
+}
+
+/*
+parseTag splits a `gwargs:"..."` struct tag into its component
+settings. The tag is a comma-separated list of key=value pairs,
+plus the bare keyword `required`:
+
+	gwargs:"name=verbose,short=v,default=false,required,help=enable verbose output"
+
+`env=NAME` additionally names an environment variable to fall
+back to; see resolveField for the full resolution order.
+Unknown keys are ignored so that tags can grow without breaking
+older callers.
+*/
+func parseTag(tag string) tagInfo {
+	var info tagInfo
+
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "name":
+			info.name = value
+		case "short":
+			info.short = value
+		case "default":
+			info.def = value
+		case "required":
+			info.required = true
+		case "help":
+			info.help = value
+		case "env":
+			info.env = value
+		}
+	}
+
+	return info
+}
+
+/*
+PrintUsage walks the fields of s, which must be a struct or a
+pointer to one, and prints a generated usage listing built from
+each field's `gwargs` tag. Fields without a `name` tag fall back
+to their Go field name, and fields without a `help` tag are
+listed with no description.
+*/
+func PrintUsage(s any) {
+	for _, line := range usageLines(s) {
+		fmt.Println(line)
+	}
+}
+
+// usageLines renders the same listing as PrintUsage, one entry per
+// field (an entry with help text spans two lines), so it can be reused by callers that need the text rather than
+// a direct print (e.g. Dispatch's subcommand listing).
+func usageLines(s any) []string {
+	t := reflect.TypeOf(s)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var lines []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := parseTag(field.Tag.Get("gwargs"))
+
+		name := tag.name
+		if name == "" {
+			name = field.Name
+		}
+
+		flag := "--" + name
+		if tag.short != "" {
+			flag += ", -" + tag.short
+		}
+
+		if tag.help != "" {
+			lines = append(lines, fmt.Sprintf("  %s\n      %s", flag, tag.help))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s", flag))
+		}
+	}
+
+	return lines
+}
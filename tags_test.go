@@ -0,0 +1,89 @@
+package gwargs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTagParsesAllKeys(t *testing.T) {
+	tag := parseTag("name=verbose,short=v,default=false,required,help=enable verbose output,env=VERBOSE")
+
+	if tag.name != "verbose" {
+		t.Errorf("name = %q, want %q", tag.name, "verbose")
+	}
+	if tag.short != "v" {
+		t.Errorf("short = %q, want %q", tag.short, "v")
+	}
+	if tag.def != "false" {
+		t.Errorf("def = %q, want %q", tag.def, "false")
+	}
+	if !tag.required {
+		t.Error("required should be true")
+	}
+	if tag.help != "enable verbose output" {
+		t.Errorf("help = %q, want %q", tag.help, "enable verbose output")
+	}
+	if tag.env != "VERBOSE" {
+		t.Errorf("env = %q, want %q", tag.env, "VERBOSE")
+	}
+}
+
+func TestParseRequiredFieldMissingReportsAll(t *testing.T) {
+	var s struct {
+		Name string `gwargs:"name=name,required"`
+		Port int    `gwargs:"name=port,required"`
+	}
+
+	err := parse(&s, nil, &Config{})
+	if err == nil {
+		t.Fatal("expected an error for missing required fields")
+	}
+	if !strings.Contains(err.Error(), "name") || !strings.Contains(err.Error(), "port") {
+		t.Errorf("error %q should mention both missing fields", err.Error())
+	}
+}
+
+func TestParseShortFlagOnNonBoolFieldFailsFast(t *testing.T) {
+	var s struct {
+		Port int `gwargs:"name=port,short=p"`
+	}
+
+	err := parse(&s, []string{"-p", "8080"}, &Config{})
+	if err == nil {
+		t.Fatal("expected an error for a short flag on a non-bool field")
+	}
+	if !strings.Contains(err.Error(), "short") {
+		t.Errorf("error %q should explain the short-flag restriction", err.Error())
+	}
+}
+
+func TestParseShortFlagOnBoolFieldWorks(t *testing.T) {
+	var s struct {
+		Verbose bool `gwargs:"name=verbose,short=v"`
+	}
+
+	if err := parse(&s, []string{"-v"}, &Config{}); err != nil {
+		t.Fatalf("parse(-v) returned error: %v", err)
+	}
+	if !s.Verbose {
+		t.Error("Verbose should be true when -v is passed")
+	}
+}
+
+func TestUsageLines(t *testing.T) {
+	var s struct {
+		Verbose bool `gwargs:"name=verbose,short=v,help=enable verbose output"`
+		Name    string
+	}
+
+	lines := usageLines(&s)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "--verbose, -v") || !strings.Contains(lines[0], "enable verbose output") {
+		t.Errorf("lines[0] = %q, missing expected content", lines[0])
+	}
+	if !strings.Contains(lines[1], "--Name") {
+		t.Errorf("lines[1] = %q, want it to fall back to the Go field name", lines[1])
+	}
+}
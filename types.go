@@ -0,0 +1,227 @@
+package gwargs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Unmarshaler is implemented by types that want to parse their own
+command-line value instead of relying on gwargs' built-in type
+handling. It takes priority over every other type, including
+time.Duration, time.Time, and encoding.TextUnmarshaler.
+*/
+type Unmarshaler interface {
+	UnmarshalArg(value string) error
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+/*
+assignSpecial handles the field types that can't be driven purely
+off reflect.Kind: time.Duration, time.Time, and any type
+implementing Unmarshaler or encoding.TextUnmarshaler. handled
+reports whether val matched one of these cases, in which case the
+caller should move on to the next field regardless of err.
+*/
+func assignSpecial(val reflect.Value, value string, present bool) (handled bool, err error) {
+	if val.CanAddr() {
+		addr := val.Addr()
+
+		if u, ok := addr.Interface().(Unmarshaler); ok {
+			if !present {
+				return true, nil
+			}
+			return true, u.UnmarshalArg(value)
+		}
+
+		if u, ok := addr.Interface().(encoding.TextUnmarshaler); ok {
+			if !present {
+				return true, nil
+			}
+			return true, u.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch val.Type() {
+	case durationType:
+		if !present {
+			return true, nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return true, err
+		}
+		val.SetInt(int64(d))
+		return true, nil
+
+	case timeType:
+		if !present {
+			return true, nil
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return true, err
+		}
+		val.Set(reflect.ValueOf(parsed))
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// assignScalar sets val, a string, bool, or numeric field, from a
+// single string value.
+func assignScalar(val reflect.Value, value, fieldName string) error {
+	switch val.Kind() {
+
+	case reflect.String:
+		val.SetString(value)
+
+	case reflect.Bool:
+		if value == "" {
+			val.SetBool(true)
+		} else {
+			val.SetBool(strings.EqualFold(value, "true"))
+		}
+
+	case
+		reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64:
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		if ok := checkIntOverflow(val, n); !ok {
+			return fmt.Errorf("overflow detected, cannot fit %v into %s", n, val.Kind())
+		}
+		val.SetInt(n)
+
+	case
+		reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64:
+
+		if strings.Contains(value, "-") {
+			return fmt.Errorf(
+				"underflow detected, cannot fit '%v' into '%s'",
+				value,
+				val.Kind(),
+			)
+		}
+
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if ok := checkUIntOverflow(val, n); !ok {
+			return fmt.Errorf("overflow detected, cannot fit %v into %s", n, val.Kind())
+		}
+		val.SetUint(n)
+
+	case
+		reflect.Float32,
+		reflect.Float64:
+
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		if ok := checkFloatOverflow(val, n); !ok {
+			return fmt.Errorf("overflow detected, cannot fit %v into %s", n, val.Kind())
+		}
+		val.SetFloat(n)
+
+	case
+		reflect.Complex64,
+		reflect.Complex128:
+
+		n, err := strconv.ParseComplex(value, 128)
+		if err != nil {
+			return err
+		}
+		if ok := checkComplexOverflow(val, n); !ok {
+			return fmt.Errorf("overflow detected, cannot fit %v into %s", n, val.Kind())
+		}
+		val.SetComplex(n)
+
+	default:
+		return fmt.Errorf("unsupported type '%s' in field '%s'", val.Kind(), fieldName)
+	}
+
+	return nil
+}
+
+// assignSlice populates a slice field from every raw occurrence
+// collected for its flag. A single comma-separated occurrence is
+// split into elements; multiple occurrences are each treated as one
+// element.
+func assignSlice(val reflect.Value, rawValues []string, fieldName string) error {
+	if len(rawValues) == 1 && strings.Contains(rawValues[0], ",") {
+		rawValues = strings.Split(rawValues[0], ",")
+	}
+
+	elemType := val.Type().Elem()
+	slice := reflect.MakeSlice(val.Type(), 0, len(rawValues))
+
+	for _, raw := range rawValues {
+		elem := reflect.New(elemType).Elem()
+		if ok, err := assignSpecial(elem, raw, true); ok {
+			if err != nil {
+				return err
+			}
+		} else if err := assignScalar(elem, raw, fieldName); err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, elem)
+	}
+
+	val.Set(slice)
+	return nil
+}
+
+// assignMap populates a string-keyed map field from every "key=value"
+// occurrence collected for its flag.
+func assignMap(val reflect.Value, rawValues []string, fieldName string) error {
+	if val.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("map field '%s' must have a string key type", fieldName)
+	}
+
+	elemType := val.Type().Elem()
+	m := reflect.MakeMapWithSize(val.Type(), len(rawValues))
+
+	for _, raw := range rawValues {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok {
+			return fmt.Errorf("expected 'key=value' for field '%s', got '%s'", fieldName, raw)
+		}
+
+		elem := reflect.New(elemType).Elem()
+		if handled, err := assignSpecial(elem, value, true); handled {
+			if err != nil {
+				return err
+			}
+		} else if err := assignScalar(elem, value, fieldName); err != nil {
+			return err
+		}
+
+		m.SetMapIndex(reflect.ValueOf(key), elem)
+	}
+
+	val.Set(m)
+	return nil
+}
@@ -0,0 +1,110 @@
+package gwargs
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func newScalar(v any) reflect.Value {
+	return reflect.New(reflect.TypeOf(v)).Elem()
+}
+
+func TestAssignScalarFloat32Negative(t *testing.T) {
+	val := newScalar(float32(0))
+
+	if err := assignScalar(val, "-1.5", "x"); err != nil {
+		t.Fatalf("assignScalar(-1.5) into float32 returned error: %v", err)
+	}
+
+	if got := float32(val.Float()); got != -1.5 {
+		t.Fatalf("got %v, want -1.5", got)
+	}
+}
+
+func TestAssignScalarFloat64Negative(t *testing.T) {
+	val := newScalar(float64(0))
+
+	if err := assignScalar(val, "-1.5", "x"); err != nil {
+		t.Fatalf("assignScalar(-1.5) into float64 returned error: %v", err)
+	}
+
+	if got := val.Float(); got != -1.5 {
+		t.Fatalf("got %v, want -1.5", got)
+	}
+}
+
+func TestAssignScalarIntOverflow(t *testing.T) {
+	val := newScalar(int8(0))
+
+	if err := assignScalar(val, "127", "x"); err != nil {
+		t.Fatalf("assignScalar(127) into int8 returned error: %v", err)
+	}
+	if err := assignScalar(val, "128", "x"); err == nil {
+		t.Fatal("assignScalar(128) into int8 should have overflowed")
+	}
+	if err := assignScalar(val, "-128", "x"); err != nil {
+		t.Fatalf("assignScalar(-128) into int8 returned error: %v", err)
+	}
+}
+
+func TestAssignScalarUintOverflow(t *testing.T) {
+	val := newScalar(uint8(0))
+
+	if err := assignScalar(val, "255", "x"); err != nil {
+		t.Fatalf("assignScalar(255) into uint8 returned error: %v", err)
+	}
+	if err := assignScalar(val, "256", "x"); err == nil {
+		t.Fatal("assignScalar(256) into uint8 should have overflowed")
+	}
+	if err := assignScalar(val, "-1", "x"); err == nil {
+		t.Fatal("assignScalar(-1) into uint8 should have underflowed")
+	}
+}
+
+func TestAssignScalarFloatOverflow(t *testing.T) {
+	val := newScalar(float32(0))
+
+	if err := assignScalar(val, "3.4e38", "x"); err != nil {
+		t.Fatalf("assignScalar(3.4e38) into float32 returned error: %v", err)
+	}
+	if err := assignScalar(val, "3.5e38", "x"); err == nil {
+		t.Fatal("assignScalar(3.5e38) into float32 should have overflowed")
+	}
+}
+
+func TestCheckIntOverflowBoundaries(t *testing.T) {
+	val := newScalar(int16(0))
+
+	if !checkIntOverflow(val, math.MaxInt16) {
+		t.Fatal("MaxInt16 should fit in int16")
+	}
+	if checkIntOverflow(val, math.MaxInt16+1) {
+		t.Fatal("MaxInt16+1 should overflow int16")
+	}
+}
+
+func TestCheckUIntOverflowBoundaries(t *testing.T) {
+	val := newScalar(uint16(0))
+
+	if !checkUIntOverflow(val, math.MaxUint16) {
+		t.Fatal("MaxUint16 should fit in uint16")
+	}
+	if checkUIntOverflow(val, math.MaxUint16+1) {
+		t.Fatal("MaxUint16+1 should overflow uint16")
+	}
+}
+
+func TestCheckFloatOverflowBoundaries(t *testing.T) {
+	val := newScalar(float32(0))
+
+	if !checkFloatOverflow(val, math.MaxFloat32) {
+		t.Fatal("MaxFloat32 should fit in float32")
+	}
+	if checkFloatOverflow(val, math.MaxFloat64) {
+		t.Fatal("MaxFloat64 should overflow float32")
+	}
+	if !checkFloatOverflow(val, -1.5) {
+		t.Fatal("-1.5 should fit in float32 without underflowing")
+	}
+}